@@ -0,0 +1,301 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Resource — тип ресурса, на который выдан Grant. "*" покрывает все типы.
+type Resource string
+
+const (
+	ResourceVacancy     Resource = "vacancy"
+	ResourceApplication Resource = "application"
+	ResourceAny         Resource = "*"
+)
+
+// Permission — уровень доступа, который даёт Grant.
+type Permission string
+
+const (
+	PermissionReadWrite Permission = "read-write"
+	PermissionReadOnly  Permission = "read-only"
+	PermissionWriteOnly Permission = "write-only"
+	PermissionDeny      Permission = "deny"
+)
+
+// Op — операция, проверяемая CheckAccess.
+type Op string
+
+const (
+	OpRead  Op = "read"
+	OpWrite Op = "write"
+)
+
+func (p Permission) allows(op Op) bool {
+	switch p {
+	case PermissionReadWrite:
+		return true
+	case PermissionReadOnly:
+		return op == OpRead
+	case PermissionWriteOnly:
+		return op == OpWrite
+	default: // PermissionDeny и любое неизвестное значение
+		return false
+	}
+}
+
+// Grant — точечное разрешение пользователя на ресурс, по образцу модели
+// доступа ntfy: {UserID, Resource, ResourceID, Permission}. ResourceID
+// может быть конкретным ID или "*" (все ресурсы данного типа).
+type Grant struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"index" json:"userId"`
+	Resource   Resource   `json:"resource"`
+	ResourceID string     `json:"resourceId"`
+	Permission Permission `json:"permission"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// MigrateACL мигрирует таблицу грантов.
+func (m *Manager) MigrateACL() error {
+	return m.db.AutoMigrate(&Grant{})
+}
+
+// roleDefaultPermission — разрешение, которое роль даёт по умолчанию при
+// отсутствии более специфичного Grant.
+func roleDefaultPermission(role Role) Permission {
+	switch role {
+	case RoleAdmin, RoleEditor:
+		return PermissionReadWrite
+	default:
+		return PermissionReadOnly
+	}
+}
+
+// specificity ранжирует Grant от наименее к наиболее специфичному:
+// точный Resource даёт +2, точный ResourceID — +1, так что
+// {vacancy, 4} (3) обгоняет {vacancy, *} (2), а тот — {*, *} (0).
+func specificity(g Grant, resource Resource, resourceID string) int {
+	score := 0
+	if g.Resource == resource {
+		score += 2
+	}
+	if g.ResourceID == resourceID {
+		score += 1
+	}
+	return score
+}
+
+// CheckAccess отвечает, разрешено ли user выполнить операцию op над
+// resource/resourceID. Среди применимых грантов побеждает самый
+// специфичный; при равной специфичности deny побеждает allow
+// (most-specific-deny wins, затем most-specific-allow). При отсутствии
+// применимых грантов используется разрешение по умолчанию для роли.
+func (m *Manager) CheckAccess(user *User, resource Resource, resourceID string, op Op) bool {
+	var grants []Grant
+	if err := m.db.Where("user_id = ? AND (resource = ? OR resource = ?)", user.ID, resource, ResourceAny).Find(&grants).Error; err != nil {
+		return false
+	}
+
+	bestSpec := -1
+	bestPerm := roleDefaultPermission(user.Role)
+
+	for _, g := range grants {
+		if g.ResourceID != resourceID && g.ResourceID != "*" {
+			continue
+		}
+		spec := specificity(g, resource, resourceID)
+		switch {
+		case spec > bestSpec:
+			bestSpec, bestPerm = spec, g.Permission
+		case spec == bestSpec && g.Permission == PermissionDeny:
+			bestPerm = PermissionDeny
+		}
+	}
+
+	return bestPerm.allows(op)
+}
+
+// AccessibleIDs вычисляет видимость коллекции resource для user на операцию
+// op: allowAll сообщает, разрешён ли ресурс целиком (грантами на "*" или
+// разрешением роли по умолчанию), а allow/deny — точечные ResourceID,
+// явно разрешённые или запрещённые поверх этого решения. Используется
+// списковыми хендлерами, чтобы per-ID гранты реально сужали выборку в БД,
+// а не только проверялись для одиночных эндпоинтов (см. CheckAccess).
+func (m *Manager) AccessibleIDs(user *User, resource Resource, op Op) (allowAll bool, allow, deny []string, err error) {
+	var grants []Grant
+	if err = m.db.Where("user_id = ? AND (resource = ? OR resource = ?)", user.ID, resource, ResourceAny).Find(&grants).Error; err != nil {
+		return false, nil, nil, err
+	}
+
+	bestSpec := -1
+	bestPerm := roleDefaultPermission(user.Role)
+	for _, g := range grants {
+		if g.ResourceID != "*" {
+			continue
+		}
+		spec := specificity(g, resource, "*")
+		switch {
+		case spec > bestSpec:
+			bestSpec, bestPerm = spec, g.Permission
+		case spec == bestSpec && g.Permission == PermissionDeny:
+			bestPerm = PermissionDeny
+		}
+	}
+	allowAll = bestPerm.allows(op)
+
+	for _, g := range grants {
+		if g.ResourceID == "*" {
+			continue
+		}
+		if g.Permission.allows(op) {
+			allow = append(allow, g.ResourceID)
+		} else {
+			deny = append(deny, g.ResourceID)
+		}
+	}
+	return allowAll, allow, deny, nil
+}
+
+// UpsertGrant создаёт или обновляет грант пользователя на ресурс (используется
+// и HTTP-хендлером, и CLI-подкомандой access).
+func (m *Manager) UpsertGrant(userID uint, resource Resource, resourceID string, perm Permission) (*Grant, error) {
+	var g Grant
+	err := m.db.Where("user_id = ? AND resource = ? AND resource_id = ?", userID, resource, resourceID).First(&g).Error
+	switch {
+	case err == nil:
+		g.Permission = perm
+		if err := m.db.Save(&g).Error; err != nil {
+			return nil, err
+		}
+	default:
+		g = Grant{UserID: userID, Resource: resource, ResourceID: resourceID, Permission: perm}
+		if err := m.db.Create(&g).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &g, nil
+}
+
+// GrantsForUser возвращает гранты пользователя вместе с разрешением по
+// умолчанию, которое даёт его роль.
+func (m *Manager) GrantsForUser(userID uint) ([]Grant, Permission, error) {
+	var user User
+	if err := m.db.First(&user, userID).Error; err != nil {
+		return nil, "", err
+	}
+	var grants []Grant
+	if err := m.db.Where("user_id = ?", userID).Find(&grants).Error; err != nil {
+		return nil, "", err
+	}
+	return grants, roleDefaultPermission(user.Role), nil
+}
+
+// ListAccessHandler отдаёт гранты пользователя и дефолтное разрешение его роли.
+func (m *Manager) ListAccessHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Неверный формат ID", http.StatusBadRequest)
+		return
+	}
+	grants, roleDefault, err := m.GrantsForUser(uint(id))
+	if err != nil {
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"grants":      grants,
+		"roleDefault": roleDefault,
+	})
+}
+
+// CreateAccessHandler создаёт или обновляет грант пользователя.
+func (m *Manager) CreateAccessHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Неверный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	var payload struct {
+		Resource   Resource   `json:"resource"`
+		ResourceID string     `json:"resourceId"`
+		Permission Permission `json:"permission"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if payload.ResourceID == "" {
+		payload.ResourceID = "*"
+	}
+
+	grant, err := m.UpsertGrant(uint(id), payload.Resource, payload.ResourceID, payload.Permission)
+	if err != nil {
+		http.Error(w, "Ошибка сохранения доступа: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(grant)
+}
+
+// DeleteAccessHandler удаляет один грант пользователя по его ID.
+func (m *Manager) DeleteAccessHandler(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+	grantID := mux.Vars(r)["grantId"]
+	if err := m.db.Where("user_id = ? AND id = ?", userID, grantID).Delete(&Grant{}).Error; err != nil {
+		http.Error(w, "Ошибка удаления доступа", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequireAccess возвращает middleware, проверяющую CheckAccess для
+// пользователя, сохранённого в контексте запроса предыдущим RequireAuth.
+// idParam — имя mux-переменной с ID ресурса ("" означает "*", т.е. весь
+// тип ресурса, как в коллекционных GET/POST-эндпоинтах).
+func (m *Manager) RequireAccess(resource Resource, op Op, idParam string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := UserFromContext(r)
+			if !ok {
+				http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+				return
+			}
+
+			resourceID := "*"
+			if idParam != "" {
+				resourceID = mux.Vars(r)[idParam]
+			}
+
+			if !m.CheckAccess(user, resource, resourceID, op) {
+				http.Error(w, "Недостаточно прав для этого ресурса", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ParseResourceSpec разбирает "<resource>[/<id>]" из CLI-аргумента в
+// (Resource, ResourceID), подставляя "*" при отсутствии ID.
+func ParseResourceSpec(spec string) (Resource, string, error) {
+	for i, r := range spec {
+		if r == '/' {
+			return Resource(spec[:i]), spec[i+1:], nil
+		}
+	}
+	if spec == "" {
+		return "", "", fmt.Errorf("пустая спецификация ресурса")
+	}
+	return Resource(spec), "*", nil
+}
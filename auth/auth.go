@@ -0,0 +1,276 @@
+// Package auth реализует сессионную аутентификацию и ролевой доступ для
+// admin-панели: модель User, хранилище сессий на cookie и middleware
+// RequireAuth, которой оборачиваются все маршруты /admin/*.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Role определяет уровень доступа пользователя к admin API.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// rank упорядочивает роли от меньшей к большей привилегии, чтобы RequireAuth
+// могла проверять достаточность прав одним числовым сравнением.
+var rank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+func (r Role) valid() bool {
+	_, ok := rank[r]
+	return ok
+}
+
+// User представляет учётную запись администратора/редактора панели управления.
+type User struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Email        string    `gorm:"uniqueIndex" json:"email"`
+	PasswordHash string    `json:"-"`
+	Role         Role      `json:"role"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// SetPassword хеширует и сохраняет пароль пользователя.
+func (u *User) SetPassword(password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = string(hash)
+	return nil
+}
+
+// CheckPassword сверяет пароль с сохранённым хешем.
+func (u *User) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+const (
+	sessionName    = "vacancies_admin_session"
+	sessionUserKey = "userId"
+	sessionCSRFKey = "csrfToken"
+	csrfHeaderName = "X-CSRF-Token"
+)
+
+// Manager инкапсулирует доступ к пользователям и сессиям admin-панели.
+type Manager struct {
+	db    *gorm.DB
+	store sessions.Store
+}
+
+// NewManager создаёт Manager поверх переданного соединения с БД. sessionKey
+// используется для подписи cookie сессий (см. gorilla/sessions). secureCookies
+// должен соответствовать реальному деплою: true, только если сервис стоит за
+// TLS-терминирующим прокси (сам он TLS не поднимает) — иначе браузер молча
+// откажется сохранять cookie и логин не удержится. HttpOnly и SameSite
+// выставлены явно: cookie сессии не должна быть доступна из document.cookie
+// (иначе XSS на admin-origin отдаёт сессию напрямую), а SameSite=Lax
+// достаточен при наличии отдельной проверки CSRF-токена (см. RequireAuth).
+func NewManager(db *gorm.DB, sessionKey []byte, secureCookies bool) *Manager {
+	store := sessions.NewCookieStore(sessionKey)
+	store.Options = &sessions.Options{
+		Path:     "/",
+		MaxAge:   86400 * 30,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   secureCookies,
+	}
+	return &Manager{db: db, store: store}
+}
+
+// Migrate мигрирует таблицу пользователей.
+func (m *Manager) Migrate() error {
+	return m.db.AutoMigrate(&User{})
+}
+
+// EnsureBootstrapAdmin создаёт первого администратора из конфигурации, если
+// в таблице ещё нет ни одного пользователя. Вызывается один раз при старте.
+func (m *Manager) EnsureBootstrapAdmin(email, password string) error {
+	if email == "" || password == "" {
+		return nil
+	}
+	var count int64
+	if err := m.db.Model(&User{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := m.CreateUser(email, password, RoleAdmin)
+	return err
+}
+
+// CreateUser создаёт нового пользователя с заданной ролью (используется
+// хендлером регистрации и CLI-подкомандой adduser).
+func (m *Manager) CreateUser(email, password string, role Role) (*User, error) {
+	if !role.valid() {
+		return nil, errors.New("неизвестная роль")
+	}
+	u := &User{Email: email, Role: role}
+	if err := u.SetPassword(password); err != nil {
+		return nil, err
+	}
+	if err := m.db.Create(u).Error; err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func genToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// LoginHandler проверяет email/пароль и заводит сессию с CSRF-токеном.
+func (m *Manager) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var user User
+	if err := m.db.Where("email = ?", payload.Email).First(&user).Error; err != nil || !user.CheckPassword(payload.Password) {
+		http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+		return
+	}
+
+	csrfToken, err := genToken()
+	if err != nil {
+		http.Error(w, "Ошибка создания сессии", http.StatusInternalServerError)
+		return
+	}
+
+	session, _ := m.store.New(r, sessionName)
+	session.Values[sessionUserKey] = user.ID
+	session.Values[sessionCSRFKey] = csrfToken
+	if err := session.Save(r, w); err != nil {
+		http.Error(w, "Ошибка сохранения сессии", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user":      user,
+		"csrfToken": csrfToken,
+	})
+}
+
+// LogoutHandler завершает текущую сессию.
+func (m *Manager) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	session, err := m.store.Get(r, sessionName)
+	if err == nil {
+		session.Options.MaxAge = -1
+		session.Save(r, w)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegisterHandler создаёт нового пользователя. Маршрут защищён RequireAuth(RoleAdmin),
+// поэтому вызывать его может только уже залогиненный администратор.
+func (m *Manager) RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		Role     Role   `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	user, err := m.CreateUser(payload.Email, payload.Password, payload.Role)
+	if err != nil {
+		http.Error(w, "Ошибка создания пользователя: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+type contextKey string
+
+const userContextKey contextKey = "auth.currentUser"
+
+// UserFromContext возвращает пользователя, сохранённого RequireAuth в контексте запроса.
+func UserFromContext(r *http.Request) (*User, bool) {
+	u, ok := r.Context().Value(userContextKey).(*User)
+	return u, ok
+}
+
+// stateChanging сообщает, нужно ли для метода запроса проверять CSRF-токен.
+func stateChanging(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// RequireAuth возвращает middleware, требующую активную сессию с ролью не ниже minRole.
+// Для запросов, изменяющих состояние (POST/PUT/PATCH/DELETE), дополнительно
+// сверяет заголовок X-CSRF-Token со значением, выданным при логине.
+func (m *Manager) RequireAuth(minRole Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, err := m.store.Get(r, sessionName)
+			if err != nil {
+				http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+				return
+			}
+
+			userID, ok := session.Values[sessionUserKey].(uint)
+			if !ok {
+				http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+				return
+			}
+
+			if stateChanging(r.Method) {
+				token, _ := session.Values[sessionCSRFKey].(string)
+				if token == "" || token != r.Header.Get(csrfHeaderName) {
+					http.Error(w, "Неверный CSRF-токен", http.StatusForbidden)
+					return
+				}
+			}
+
+			var user User
+			if err := m.db.First(&user, userID).Error; err != nil {
+				http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+				return
+			}
+			if rank[user.Role] < rank[minRole] {
+				http.Error(w, "Недостаточно прав", http.StatusForbidden)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userContextKey, &user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
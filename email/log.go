@@ -0,0 +1,58 @@
+package email
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Log фиксирует одну попытку отправки шаблонного письма (send-template) для
+// последующего просмотра через GET /admin/application/{id}/emails. Письма,
+// отправленные через send-email, в журнал не записываются.
+type Log struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ApplicationID uint      `gorm:"index" json:"applicationId"`
+	Template      string    `json:"template"`
+	Recipient     string    `json:"recipient"`
+	Subject       string    `json:"subject"`
+	Status        string    `json:"status"` // "sent" или "error"
+	Error         string    `json:"error,omitempty"`
+	SentAt        time.Time `json:"sentAt"`
+}
+
+// LogStore сохраняет и читает историю отправленных писем.
+type LogStore struct {
+	db *gorm.DB
+}
+
+// NewLogStore создаёт LogStore и мигрирует таблицу Log.
+func NewLogStore(db *gorm.DB) (*LogStore, error) {
+	if err := db.AutoMigrate(&Log{}); err != nil {
+		return nil, err
+	}
+	return &LogStore{db: db}, nil
+}
+
+// Record сохраняет результат одной попытки отправки письма.
+func (s *LogStore) Record(applicationID uint, template, recipient, subject string, sendErr error) error {
+	entry := Log{
+		ApplicationID: applicationID,
+		Template:      template,
+		Recipient:     recipient,
+		Subject:       subject,
+		Status:        "sent",
+		SentAt:        time.Now(),
+	}
+	if sendErr != nil {
+		entry.Status = "error"
+		entry.Error = sendErr.Error()
+	}
+	return s.db.Create(&entry).Error
+}
+
+// ForApplication возвращает историю писем заявки, от новых к старым.
+func (s *LogStore) ForApplication(applicationID uint) ([]Log, error) {
+	var logs []Log
+	err := s.db.Where("application_id = ?", applicationID).Order("sent_at desc").Find(&logs).Error
+	return logs, err
+}
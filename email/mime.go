@@ -0,0 +1,94 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message — отправляемое письмо с текстовой и HTML версиями тела.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Build собирает корректное MIME-сообщение multipart/alternative с
+// заголовками From/To/Subject/MIME-Version/Date/Message-ID и
+// quoted-printable кодированием обеих частей, заменяя прежнюю ad-hoc
+// конкатенацию строк в sendEmail.
+func (m Message) Build(now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@vacancies-service>\r\n", uuid.NewString())
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+
+	if err := writePart(writer, "text/plain; charset=utf-8", m.Text); err != nil {
+		return nil, err
+	}
+	if err := writePart(writer, "text/html; charset=utf-8", m.HTML); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writePart(w *multipart.Writer, contentType, body string) error {
+	header := make(map[string][]string)
+	header["Content-Type"] = []string{contentType}
+	header["Content-Transfer-Encoding"] = []string{"quoted-printable"}
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// Sender отправляет готовые MIME-сообщения через SMTP.
+type Sender struct {
+	Addr     string
+	Auth     smtp.Auth
+	FromAddr string
+}
+
+// NewSender создаёт Sender с PLAIN-аутентификацией по переданным учётным данным.
+func NewSender(host string, port int, username, password string) *Sender {
+	return &Sender{
+		Addr:     fmt.Sprintf("%s:%d", host, port),
+		Auth:     smtp.PlainAuth("", username, password, host),
+		FromAddr: username,
+	}
+}
+
+// Send рендерит сообщение и отправляет его указанному получателю.
+func (s *Sender) Send(to string, msg Message, now time.Time) error {
+	msg.From = s.FromAddr
+	msg.To = to
+	raw, err := msg.Build(now)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(s.Addr, s.Auth, s.FromAddr, []string{to}, raw)
+}
@@ -0,0 +1,140 @@
+// Package email рендерит шаблоны писем (Markdown + text/template) в
+// multipart/alternative сообщения и отправляет их по SMTP, заменяя старую
+// отправку "сырых" subject/body строк в main.sendEmail.
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+// ApplicationView — данные заявки, доступные шаблону письма.
+type ApplicationView struct {
+	ID                uint
+	Name              string
+	PrimaryContact    string
+	About             string
+	Status            string
+	SalaryExpectation string
+	AvailableFrom     string
+	CreatedAt         time.Time
+}
+
+// VacancyView — данные вакансии, на которую подана заявка.
+type VacancyView struct {
+	ID       uint
+	Title    string
+	Subtitle string
+}
+
+// Context — контекст рендеринга одного письма.
+type Context struct {
+	Application ApplicationView
+	Vacancy     VacancyView
+	Now         time.Time
+	Lang        string // "en" или "ru"; по умолчанию "en"
+	Vars        map[string]string
+}
+
+func (c Context) funcMap() template.FuncMap {
+	return template.FuncMap{
+		"timeAgo": func(t time.Time) string { return TimeAgo(t, c.Now, c.Lang) },
+	}
+}
+
+// tpl — пара шаблонов subject+body одного именованного письма
+// (например, "application_accepted").
+type tpl struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// Loader читает именованные шаблоны писем из каталога на диске:
+// "<name>.subject.txt" — заголовок, "<name>.md" — тело в Markdown.
+type Loader struct {
+	mu        sync.RWMutex
+	templates map[string]*tpl
+}
+
+// LoadTemplates сканирует каталог dir и собирает шаблоны по паре файлов
+// "<name>.md" + "<name>.subject.txt".
+func LoadTemplates(dir string) (*Loader, error) {
+	l := &Loader{templates: make(map[string]*tpl)}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("чтение каталога шаблонов: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		name := e.Name()[:len(e.Name())-len(".md")]
+
+		body, err := os.ReadFile(filepath.Join(dir, name+".md"))
+		if err != nil {
+			return nil, fmt.Errorf("шаблон %s: %w", name, err)
+		}
+		subject, err := os.ReadFile(filepath.Join(dir, name+".subject.txt"))
+		if err != nil {
+			return nil, fmt.Errorf("заголовок шаблона %s: %w", name, err)
+		}
+
+		// timeAgo должна быть объявлена уже при разборе; Render подменяет её
+		// версией, привязанной к Context.Now/Lang конкретного письма.
+		bodyTpl, err := template.New(name + ".md").Funcs(Context{}.funcMap()).Parse(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("разбор шаблона %s: %w", name, err)
+		}
+		subjectTpl, err := template.New(name + ".subject").Funcs(Context{}.funcMap()).Parse(string(subject))
+		if err != nil {
+			return nil, fmt.Errorf("разбор заголовка %s: %w", name, err)
+		}
+
+		l.templates[name] = &tpl{subject: subjectTpl, body: bodyTpl}
+	}
+
+	return l, nil
+}
+
+// MarkdownToHTML конвертирует Markdown в HTML — используется и для шаблонных
+// писем, и для устаревшего plain-текстового sendEmail.
+func MarkdownToHTML(markdown string) string {
+	return string(blackfriday.Run([]byte(markdown)))
+}
+
+// Render рендерит шаблон name контекстом ctx и возвращает заголовок письма,
+// HTML-версию (Markdown -> HTML) и исходный текст тела для text/plain части.
+func (l *Loader) Render(name string, ctx Context) (subject, html, text string, err error) {
+	l.mu.RLock()
+	t, ok := l.templates[name]
+	l.mu.RUnlock()
+	if !ok {
+		return "", "", "", fmt.Errorf("неизвестный шаблон письма: %s", name)
+	}
+
+	funcs := ctx.funcMap()
+
+	var subjectBuf bytes.Buffer
+	if err := t.subject.Funcs(funcs).Execute(&subjectBuf, ctx); err != nil {
+		return "", "", "", fmt.Errorf("рендер заголовка %s: %w", name, err)
+	}
+
+	var bodyBuf bytes.Buffer
+	if err := t.body.Funcs(funcs).Execute(&bodyBuf, ctx); err != nil {
+		return "", "", "", fmt.Errorf("рендер тела %s: %w", name, err)
+	}
+
+	text = bodyBuf.String()
+	html = MarkdownToHTML(text)
+	subject = subjectBuf.String()
+	return subject, html, text, nil
+}
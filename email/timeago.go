@@ -0,0 +1,70 @@
+package email
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeAgo форматирует разницу между now и t в виде "3 hours ago" / "3 часа назад"
+// в зависимости от lang ("en" или "ru", по умолчанию "en"). Используется как
+// шаблонная функция timeAgo в письмах, где упоминается момент подачи заявки.
+func TimeAgo(t, now time.Time, lang string) string {
+	d := now.Sub(t)
+	if d < 0 {
+		d = 0
+	}
+
+	switch {
+	case d < time.Minute:
+		return pluralize(lang, 0, "second")
+	case d < time.Hour:
+		n := int(d / time.Minute)
+		return pluralize(lang, n, "minute")
+	case d < 24*time.Hour:
+		n := int(d / time.Hour)
+		return pluralize(lang, n, "hour")
+	default:
+		n := int(d / (24 * time.Hour))
+		return pluralize(lang, n, "day")
+	}
+}
+
+// pluralize собирает фразу вида "<n> <unit> назад" с русским склонением
+// для lang == "ru" и "<n> <unit>(s) ago" на английском для любого другого lang.
+func pluralize(lang string, n int, unit string) string {
+	if lang == "ru" {
+		return fmt.Sprintf("%s назад", ruUnit(n, unit))
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d %s ago", n, unit)
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
+
+// ruUnit — упрощённое (но корректное для основных случаев) склонение русских
+// единиц времени: секунда/минута/час/день с учётом 1/2-4/5-20 и 11-14.
+func ruUnit(n int, unit string) string {
+	forms := map[string][3]string{
+		"second": {"секунда", "секунды", "секунд"},
+		"minute": {"минута", "минуты", "минут"},
+		"hour":   {"час", "часа", "часов"},
+		"day":    {"день", "дня", "дней"},
+	}
+	f := forms[unit]
+	return fmt.Sprintf("%d %s", n, f[ruPluralForm(n)])
+}
+
+func ruPluralForm(n int) int {
+	n = n % 100
+	if n >= 11 && n <= 14 {
+		return 2
+	}
+	switch n % 10 {
+	case 1:
+		return 0
+	case 2, 3, 4:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,145 @@
+// Package events содержит Hub — шину событий приложения, которая питает
+// SSE-эндпоинт /admin/events и исходящие вебхуки (webhook.Dispatcher) —
+// транспорты, которым нужно реагировать на изменения вакансий и заявок.
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Event — единица публикации в шине. ID монотонно возрастает в рамках
+// процесса и используется клиентами SSE для возобновления чтения через
+// заголовок Last-Event-ID.
+type Event struct {
+	ID    uint64      `json:"id"`
+	Topic string      `json:"topic"`
+	Name  string      `json:"name"`
+	Data  interface{} `json:"data"`
+}
+
+// ringSize — сколько последних событий каждого топика хранится для реплея.
+const ringSize = 200
+
+// subscriberBuffer — ёмкость канала одного подписчика. При переполнении
+// самое старое событие отбрасывается в пользу нового (drop-oldest).
+const subscriberBuffer = 32
+
+type ring struct {
+	events []Event
+}
+
+func (rb *ring) push(ev Event) {
+	rb.events = append(rb.events, ev)
+	if len(rb.events) > ringSize {
+		rb.events = rb.events[len(rb.events)-ringSize:]
+	}
+}
+
+func (rb *ring) since(lastID uint64) []Event {
+	var out []Event
+	for _, ev := range rb.events {
+		if ev.ID > lastID {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+type topicState struct {
+	ring ring
+	subs map[chan Event]struct{}
+}
+
+// Hub — потокобезопасная шина событий с топиками, буферизованными каналами
+// подписчиков и небольшим кольцевым буфером на топик для реплея по Last-Event-ID.
+type Hub struct {
+	seq uint64 // инкрементируется atomic.AddUint64, должен идти первым полем (выравнивание)
+
+	mu     sync.Mutex
+	topics map[string]*topicState
+}
+
+// NewHub создаёт пустую шину событий.
+func NewHub() *Hub {
+	return &Hub{topics: make(map[string]*topicState)}
+}
+
+func (h *Hub) topic(name string) *topicState {
+	t, ok := h.topics[name]
+	if !ok {
+		t = &topicState{subs: make(map[chan Event]struct{})}
+		h.topics[name] = t
+	}
+	return t
+}
+
+// Subscribe регистрирует нового подписчика на топик и возвращает канал
+// событий и функцию отмены подписки. Вызывающий обязан вызвать cancel,
+// когда закончит читать из канала (например, при закрытии соединения).
+func (h *Hub) Subscribe(topic string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	h.topic(topic).subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		if t, ok := h.topics[topic]; ok {
+			delete(t.subs, ch)
+		}
+		h.mu.Unlock()
+	}
+	return ch, cancel
+}
+
+// Replay возвращает события топика с ID строго больше lastID — используется
+// для восстановления пропущенных сообщений при переподключении клиента.
+func (h *Hub) Replay(topic string, lastID uint64) []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t, ok := h.topics[topic]
+	if !ok {
+		return nil
+	}
+	return t.ring.since(lastID)
+}
+
+// Publish публикует событие name с данными data в топике topic и возвращает
+// опубликованный Event (с присвоенным ID).
+func (h *Hub) Publish(topic, name string, data interface{}) Event {
+	ev := Event{
+		ID:    atomic.AddUint64(&h.seq, 1),
+		Topic: topic,
+		Name:  name,
+		Data:  data,
+	}
+
+	h.mu.Lock()
+	t := h.topic(topic)
+	t.ring.push(ev)
+	chans := make([]chan Event, 0, len(t.subs))
+	for ch := range t.subs {
+		chans = append(chans, ch)
+	}
+	h.mu.Unlock()
+
+	for _, ch := range chans {
+		select {
+		case ch <- ev:
+		default:
+			// Подписчик не успевает читать — отбрасываем самое старое
+			// сообщение и пробуем снова, не блокируя публикацию.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- ev:
+			default:
+			}
+		}
+	}
+	return ev
+}
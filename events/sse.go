@@ -0,0 +1,148 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const keepaliveInterval = 15 * time.Second
+
+// ServeSSE обслуживает долгоживущее соединение Server-Sent Events для
+// перечисленных в topics топиков. При наличии заголовка Last-Event-ID
+// сначала реплеит пропущенные события из кольцевого буфера каждого топика,
+// затем транслирует live-события, периодически отправляя keepalive-комментарий,
+// чтобы прокси не закрывали простаивающее соединение. allowed вызывается для
+// каждого события (реплей и live) и, если возвращает false, событие молча
+// пропускается — так вызывающий может применить точечные ACL-гранты
+// подписчика, а не раздавать полные строки Application/Vacancy всем подряд.
+func (h *Hub) ServeSSE(w http.ResponseWriter, r *http.Request, topics []string, allowed func(Event) bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Потоковая передача не поддерживается", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// У /admin/events нет фиксированного времени ответа, поэтому снимаем
+	// серверный WriteTimeout именно для этого соединения (не глобально).
+	if rc := http.NewResponseController(w); rc != nil {
+		_ = rc.SetWriteDeadline(time.Time{})
+	}
+
+	var lastID uint64
+	replay := false
+	if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+		if id, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			lastID = id
+			replay = true
+		}
+	}
+
+	write := func(ev Event) error {
+		data, err := json.Marshal(ev.Data)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Name, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	for _, topic := range topics {
+		if !replay {
+			break
+		}
+		for _, ev := range h.Replay(topic, lastID) {
+			if !allowed(ev) {
+				continue
+			}
+			if err := write(ev); err != nil {
+				return
+			}
+		}
+	}
+
+	type subscription struct {
+		topic  string
+		ch     <-chan Event
+		cancel func()
+	}
+	subs := make([]subscription, 0, len(topics))
+	for _, topic := range topics {
+		ch, cancel := h.Subscribe(topic)
+		subs = append(subs, subscription{topic: topic, ch: ch, cancel: cancel})
+	}
+	defer func() {
+		for _, s := range subs {
+			s.cancel()
+		}
+	}()
+
+	merged := make(chan Event, subscriberBuffer*len(subs))
+	for _, s := range subs {
+		go func(ch <-chan Event) {
+			for {
+				select {
+				case ev, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case merged <- ev:
+					case <-r.Context().Done():
+						return
+					}
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}(s.ch)
+	}
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-merged:
+			if !allowed(ev) {
+				continue
+			}
+			if err := write(ev); err != nil {
+				return
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// ParseTopics разбирает значение query-параметра ?topics=a,b,c в список имён.
+func ParseTopics(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	topics := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			topics = append(topics, p)
+		}
+	}
+	return topics
+}
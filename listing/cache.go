@@ -0,0 +1,75 @@
+package listing
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	query    Query
+	envelope Envelope
+	expiry   time.Time
+}
+
+// Cache — кэш результатов листинга, ключованный по канонической строке
+// запроса (в отличие от прежнего "всё или ничего" vacanciesCache). Мутация
+// строки инвалидирует только те записи, чей filter мог бы относиться к этой
+// строке (негативный поиск: если известно, что строка не проходит фильтр
+// записи — ни до, ни после изменения, — трогать эту запись кэша не нужно).
+type Cache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+// NewCache создаёт кэш с временем жизни записи ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// Get возвращает закэшированный Envelope по ключу, если он ещё не истёк.
+func (c *Cache) Get(key string) (Envelope, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expiry) {
+		return Envelope{}, false
+	}
+	return e.envelope, true
+}
+
+// Set сохраняет Envelope по ключу вместе с породившим его Query — Query
+// нужен Invalidate, чтобы понять, затрагивает ли изменившаяся строка эту запись.
+func (c *Cache) Set(key string, query Query, envelope Envelope) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{query: query, envelope: envelope, expiry: time.Now().Add(c.ttl)}
+}
+
+// Invalidate удаляет записи, чьи filter[...] могли бы относиться к
+// изменившейся строке. row — известные значения изменённых колонок
+// (до или после правки); nil означает "неизвестно", и все записи
+// считаются потенциально затронутыми (используется при создании строки).
+func (c *Cache) Invalidate(row map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if mightMatch(e.query, row) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func mightMatch(q Query, row map[string]string) bool {
+	if row == nil {
+		return true
+	}
+	for _, f := range q.Filters {
+		if val, known := row[f.Column]; known && val != f.Value {
+			// Известно, что строка не проходит этот фильтр — запись кэша
+			// эту строку не содержит и не будет содержать из-за этого изменения.
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package listing
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Envelope — единый формат ответа для всех листинговых GET-эндпоинтов.
+type Envelope struct {
+	Items      interface{} `json:"items"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"pageSize"`
+	Total      int64       `json:"total"`
+	TotalPages int         `json:"totalPages"`
+}
+
+// NewEnvelope собирает Envelope из результатов страницы и общего количества.
+func NewEnvelope(items interface{}, q Query, total int64) Envelope {
+	totalPages := int(total) / q.PageSize
+	if int(total)%q.PageSize != 0 {
+		totalPages++
+	}
+	return Envelope{
+		Items:      items,
+		Page:       q.Page,
+		PageSize:   q.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+// LinkHeader строит значение заголовка Link с rel="next"/rel="prev" для
+// переданного базового URL и текущей страницы.
+func LinkHeader(base *url.URL, q Query, totalPages int) string {
+	links := make([]string, 0, 2)
+
+	withPage := func(page int) string {
+		u := *base
+		values := u.Query()
+		values.Set("page", strconv.Itoa(page))
+		u.RawQuery = values.Encode()
+		return u.String()
+	}
+
+	if q.Page < totalPages {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, withPage(q.Page+1)))
+	}
+	if q.Page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, withPage(q.Page-1)))
+	}
+
+	out := ""
+	for i, l := range links {
+		if i > 0 {
+			out += ", "
+		}
+		out += l
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+// Package listing разбирает query-параметры GET-коллекций
+// (filter[...]=..., sort=, page=, pageSize=, fields=, q=) в Query и
+// применяет его к gorm.DB через белый список разрешённых колонок на модель,
+// чтобы filter/sort/fields нельзя было использовать для SQL-инъекции через
+// имена колонок.
+package listing
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 25
+	maxPageSize     = 100
+)
+
+// Filter — одно условие равенства filter[column]=value из строки запроса.
+type Filter struct {
+	Column string
+	Value  string
+}
+
+// SortField — одно поле sort=, с учётом ведущего "-" для убывания.
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// Query — разобранные и провалидированные параметры листинга.
+type Query struct {
+	Filters  []Filter
+	Sort     []SortField
+	Page     int
+	PageSize int
+	Fields   []string
+	Search   string
+}
+
+// Columns — белый список колонок одной модели: какие JSON-имена можно
+// фильтровать/сортировать/выбирать, и в какую колонку БД они отображаются
+// (значение карты). Так filter[vacancyId] может безопасно превратиться в
+// "vacancy_id = ?", даже если JSON и SQL имена расходятся.
+type Columns struct {
+	Filterable map[string]string
+	Sortable   map[string]string
+	Selectable map[string]string
+	Searchable []string // колонки БД для LIKE-поиска по q=
+}
+
+// Parse разбирает url.Values в Query, отклоняя любые filter/sort/fields,
+// не входящие в белый список c.
+func (c Columns) Parse(values url.Values) (Query, error) {
+	q := Query{Page: 1, PageSize: defaultPageSize, Search: values.Get("q")}
+
+	for key, vals := range values {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		name := key[len("filter[") : len(key)-1]
+		column, ok := c.Filterable[name]
+		if !ok {
+			return Query{}, fmt.Errorf("фильтрация по полю %q не разрешена", name)
+		}
+		for _, v := range vals {
+			q.Filters = append(q.Filters, Filter{Column: column, Value: v})
+		}
+	}
+
+	if raw := values.Get("sort"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			desc := strings.HasPrefix(part, "-")
+			name := strings.TrimPrefix(part, "-")
+			column, ok := c.Sortable[name]
+			if !ok {
+				return Query{}, fmt.Errorf("сортировка по полю %q не разрешена", name)
+			}
+			q.Sort = append(q.Sort, SortField{Column: column, Desc: desc})
+		}
+	}
+
+	if raw := values.Get("fields"); raw != "" {
+		for _, f := range strings.Split(raw, ",") {
+			f = strings.TrimSpace(f)
+			if f == "" {
+				continue
+			}
+			column, ok := c.Selectable[f]
+			if !ok {
+				return Query{}, fmt.Errorf("поле %q нельзя выбрать", f)
+			}
+			q.Fields = append(q.Fields, column)
+		}
+	}
+
+	if raw := values.Get("page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return Query{}, fmt.Errorf("неверный page: %q", raw)
+		}
+		q.Page = n
+	}
+	if raw := values.Get("pageSize"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxPageSize {
+			return Query{}, fmt.Errorf("неверный pageSize: %q (максимум %d)", raw, maxPageSize)
+		}
+		q.PageSize = n
+	}
+
+	return q, nil
+}
+
+// where применяет filter[...] и q= (LIKE-поиск по searchColumns) к db.
+// searchColumns должны приходить из Columns.Searchable, а не из запроса.
+func (q Query) where(db *gorm.DB, searchColumns []string) *gorm.DB {
+	for _, f := range q.Filters {
+		db = db.Where(fmt.Sprintf("%s = ?", f.Column), f.Value)
+	}
+
+	if q.Search != "" && len(searchColumns) > 0 {
+		clauses := make([]string, len(searchColumns))
+		args := make([]interface{}, len(searchColumns))
+		like := "%" + q.Search + "%"
+		for i, col := range searchColumns {
+			clauses[i] = fmt.Sprintf("%s LIKE ?", col)
+			args[i] = like
+		}
+		db = db.Where(strings.Join(clauses, " OR "), args...)
+	}
+
+	return db
+}
+
+// Filtered применяет только filter/q (без order/select/пагинации) —
+// для подсчёта total перед выборкой страницы.
+func (q Query) Filtered(db *gorm.DB, searchColumns []string) *gorm.DB {
+	return q.where(db, searchColumns)
+}
+
+// Apply применяет Where/Order/Offset/Limit/Select из q к db для выборки
+// одной страницы результатов.
+func (q Query) Apply(db *gorm.DB, searchColumns []string) *gorm.DB {
+	db = q.where(db, searchColumns)
+
+	if len(q.Sort) > 0 {
+		parts := make([]string, len(q.Sort))
+		for i, s := range q.Sort {
+			dir := "ASC"
+			if s.Desc {
+				dir = "DESC"
+			}
+			parts[i] = fmt.Sprintf("%s %s", s.Column, dir)
+		}
+		db = db.Order(strings.Join(parts, ", "))
+	}
+
+	if len(q.Fields) > 0 {
+		db = db.Select(q.Fields)
+	}
+
+	return db.Offset((q.Page - 1) * q.PageSize).Limit(q.PageSize)
+}
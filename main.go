@@ -1,14 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"flag"
 	"io/ioutil"
 	"log"
 	"net/http"
-	"net/smtp"
+	"os"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -16,6 +16,12 @@ import (
 	"gorm.io/datatypes"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+
+	"vacancies_service/auth"
+	"vacancies_service/email"
+	"vacancies_service/events"
+	"vacancies_service/listing"
+	"vacancies_service/webhook"
 )
 
 // Config содержит настройки приложения.
@@ -28,6 +34,14 @@ type Config struct {
 	SMTPUsername        string   `json:"smtpUsername"`        // логин для SMTP
 	SMTPPassword        string   `json:"smtpPassword"`        // пароль для SMTP
 	AdminAllowedOrigins []string `json:"adminAllowedOrigins"` // список разрешённых доменов для CORS /admin
+
+	SessionKey             string `json:"sessionKey"`             // ключ подписи cookie сессий admin-панели
+	SessionSecureCookies   bool   `json:"sessionSecureCookies"`   // true, только если сервис стоит за TLS-терминирующим прокси
+	BootstrapAdminEmail    string `json:"bootstrapAdminEmail"`    // email первого администратора, создаётся при пустой таблице users
+	BootstrapAdminPassword string `json:"bootstrapAdminPassword"` // пароль первого администратора
+
+	TemplatesDir string `json:"templatesDir"` // каталог с шаблонами писем (*.md + *.subject.txt)
+	EmailLocale  string `json:"emailLocale"`  // "en" или "ru" — язык relative-time в письмах по умолчанию
 }
 
 // Загружаем конфигурацию из файла config.json.
@@ -71,69 +85,175 @@ type Application struct {
 }
 
 var (
-	db  *gorm.DB
-	cfg Config
-
-	// Простое in-memory кэширование для вакансий.
-	vacanciesCache      []Vacancy
-	vacanciesCacheMutex sync.RWMutex
+	db      *gorm.DB
+	cfg     Config
+	authMgr *auth.Manager
+
+	// eventsHub рассылает события жизненного цикла вакансий/заявок подписчикам
+	// admin-панели по SSE (см. /admin/events).
+	eventsHub = events.NewHub()
+
+	// Шаблоны писем, их отправитель и журнал отправок (см. пакет email).
+	emailTemplates *email.Loader
+	emailSender    *email.Sender
+	emailLogs      *email.LogStore
+
+	// webhookDispatcher рассылает события из eventsHub на внешние вебхуки
+	// (см. пакет webhook) — тот же поток событий, что питает /admin/events.
+	webhookDispatcher *webhook.Dispatcher
+
+	// vacanciesListCache кэширует результаты листинга вакансий по канонической
+	// строке запроса (filter/sort/page/...), а не все вакансии разом.
+	vacanciesListCache = listing.NewCache(30 * time.Second)
 )
 
-// invalidateVacanciesCache сбрасывает кэш вакансий.
-func invalidateVacanciesCache() {
-	vacanciesCacheMutex.Lock()
-	vacanciesCache = nil
-	vacanciesCacheMutex.Unlock()
+// vacancyColumns — белый список полей, доступных в листинге вакансий.
+var vacancyColumns = listing.Columns{
+	Filterable: map[string]string{},
+	Sortable: map[string]string{
+		"title":     "title",
+		"createdAt": "created_at",
+		"updatedAt": "updated_at",
+	},
+	Selectable: map[string]string{
+		"id":           "id",
+		"title":        "title",
+		"subtitle":     "subtitle",
+		"description":  "description",
+		"headerImage":  "header_image",
+		"bgGradient":   "bg_gradient",
+		"requirements": "requirements",
+		"techStack":    "tech_stack",
+		"createdAt":    "created_at",
+		"updatedAt":    "updated_at",
+	},
+	Searchable: []string{"title", "subtitle", "description"},
+}
+
+// applicationColumns — белый список полей, доступных в листинге заявок.
+var applicationColumns = listing.Columns{
+	Filterable: map[string]string{
+		"status":    "status",
+		"vacancyId": "vacancy_id",
+	},
+	Sortable: map[string]string{
+		"createdAt": "created_at",
+		"updatedAt": "updated_at",
+		"name":      "name",
+		"status":    "status",
+	},
+	Selectable: map[string]string{
+		"id":                 "id",
+		"primaryContact":     "primary_contact",
+		"additionalContacts": "additional_contacts",
+		"name":               "name",
+		"about":              "about",
+		"vacancyId":          "vacancy_id",
+		"status":             "status",
+		"salaryExpectation":  "salary_expectation",
+		"availableFrom":      "available_from",
+		"createdAt":          "created_at",
+		"updatedAt":          "updated_at",
+	},
+	Searchable: []string{"name", "about", "primary_contact"},
+}
+
+// vacancyFilterRow строит row для invalidateVacanciesCache: известные
+// значения колонок вакансии v, но только те из них, что разрешены к
+// фильтрации в vacancyColumns.Filterable (сейчас этот список пуст, поэтому
+// результат пуст и мутация вакансии по-прежнему сбрасывает кэш целиком —
+// значение подставится само, как только у Vacancy появится фильтруемое поле).
+func vacancyFilterRow(v Vacancy) map[string]string {
+	values := map[string]string{
+		"title":        v.Title,
+		"subtitle":     v.Subtitle,
+		"description":  v.Description,
+		"header_image": v.HeaderImage,
+		"bg_gradient":  v.BgGradient,
+	}
+	row := make(map[string]string, len(vacancyColumns.Filterable))
+	for _, column := range vacancyColumns.Filterable {
+		row[column] = values[column]
+	}
+	return row
+}
+
+// invalidateVacanciesCache инвалидирует записи кэша листинга вакансий,
+// чьи filter могли бы относиться к изменившейся строке. row передаёт
+// известные значения изменённых колонок (ключи — имена из vacancyColumns.Filterable),
+// либо nil, если строка новая и могла бы попасть в любую выборку.
+func invalidateVacanciesCache(row map[string]string) {
+	vacanciesListCache.Invalidate(row)
 }
 
 // ----------------------
 // ФУНКЦИИ ОТПРАВКИ EMAIL
 // ----------------------
 
-// sendEmail отправляет письмо через SMTP сервер согласно конфигурации.
+// sendEmail отправляет письмо как multipart/alternative сообщение: plain-текст
+// body используется как text/plain версия, а Markdown-рендер того же body —
+// как text/html версия.
 func sendEmail(to, subject, body string) error {
-	msg := "From: " + cfg.SMTPUsername + "\r\n" +
-		"To: " + to + "\r\n" +
-		"Subject: " + subject + "\r\n" +
-		"\r\n" + body
-
-	addr := fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort)
-	auth := smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
-	return smtp.SendMail(addr, auth, cfg.SMTPUsername, []string{to}, []byte(msg))
+	msg := email.Message{
+		Subject: subject,
+		Text:    body,
+		HTML:    email.MarkdownToHTML(body),
+	}
+	return emailSender.Send(to, msg, time.Now())
 }
 
 // ----------------------
 // PUBLIC API
 // ----------------------
 
-// getVacanciesHandler возвращает все вакансии, используя кэш, если он доступен.
-func getVacanciesHandler(w http.ResponseWriter, r *http.Request) {
-	vacanciesCacheMutex.RLock()
-	if vacanciesCache != nil {
-		cached := vacanciesCache
-		vacanciesCacheMutex.RUnlock()
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{"vacancies": cached})
+// listVacancies разбирает параметры листинга, отдаёт результат из
+// vacanciesListCache при попадании и выполняет запрос к БД иначе.
+func listVacancies(w http.ResponseWriter, r *http.Request) {
+	q, err := vacancyColumns.Parse(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cacheKey := r.URL.Query().Encode()
+	if envelope, ok := vacanciesListCache.Get(cacheKey); ok {
+		writeEnvelope(w, r, q, envelope)
+		return
+	}
+
+	var total int64
+	if err := q.Filtered(db.Model(&Vacancy{}), vacancyColumns.Searchable).Count(&total).Error; err != nil {
+		http.Error(w, "Ошибка запроса вакансий", http.StatusInternalServerError)
 		return
 	}
-	vacanciesCacheMutex.RUnlock()
 
 	var vacancies []Vacancy
-	if err := db.Find(&vacancies).Error; err != nil {
+	if err := q.Apply(db.Model(&Vacancy{}), vacancyColumns.Searchable).Find(&vacancies).Error; err != nil {
 		http.Error(w, "Ошибка запроса вакансий", http.StatusInternalServerError)
 		return
 	}
 
-	// Обновляем кэш
-	vacanciesCacheMutex.Lock()
-	vacanciesCache = vacancies
-	vacanciesCacheMutex.Unlock()
+	envelope := listing.NewEnvelope(vacancies, q, total)
+	vacanciesListCache.Set(cacheKey, q, envelope)
+	writeEnvelope(w, r, q, envelope)
+}
 
+// writeEnvelope сериализует Envelope и проставляет Link/X-Total-Count заголовки.
+func writeEnvelope(w http.ResponseWriter, r *http.Request, q listing.Query, envelope listing.Envelope) {
+	if link := listing.LinkHeader(r.URL, q, envelope.TotalPages); link != "" {
+		w.Header().Set("Link", link)
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(envelope.Total, 10))
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{"vacancies": vacancies})
+	json.NewEncoder(w).Encode(envelope)
 }
 
-// getVacancyHandler возвращает вакансию по ID, пытаясь сначала найти её в кэше.
+// getVacanciesHandler отдаёт список вакансий с фильтрацией/сортировкой/пагинацией.
+func getVacanciesHandler(w http.ResponseWriter, r *http.Request) {
+	listVacancies(w, r)
+}
+
+// getVacancyHandler возвращает вакансию по ID.
 func getVacancyHandler(w http.ResponseWriter, r *http.Request) {
 	idStr := mux.Vars(r)["id"]
 	id, err := strconv.ParseUint(idStr, 10, 64)
@@ -142,19 +262,6 @@ func getVacancyHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vacanciesCacheMutex.RLock()
-	if vacanciesCache != nil {
-		for _, v := range vacanciesCache {
-			if v.ID == uint(id) {
-				vacanciesCacheMutex.RUnlock()
-				w.Header().Set("Content-Type", "application/json")
-				json.NewEncoder(w).Encode(v)
-				return
-			}
-		}
-	}
-	vacanciesCacheMutex.RUnlock()
-
 	var vacancy Vacancy
 	if err := db.First(&vacancy, id).Error; err != nil {
 		http.Error(w, "Вакансия не найдена", http.StatusNotFound)
@@ -177,6 +284,7 @@ func applyHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	log.Printf("Новая заявка: %+v\n", app)
+	eventsHub.Publish("applications", "application.created", app)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]string{"status": "application received"})
@@ -198,7 +306,8 @@ func addVacancyHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Инвалидируем кэш
-	invalidateVacanciesCache()
+	invalidateVacanciesCache(nil)
+	eventsHub.Publish("vacancies", "vacancy.created", v)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -213,6 +322,7 @@ func updateVacancyHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Вакансия не найдена", http.StatusNotFound)
 		return
 	}
+	oldRow := vacancyFilterRow(v)
 	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -223,11 +333,13 @@ func updateVacancyHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Ошибка обновления вакансии", http.StatusInternalServerError)
 		return
 	}
-	// Инвалидируем кэш
-	invalidateVacanciesCache()
-
 	var updated Vacancy
 	db.First(&updated, id)
+	// Инвалидируем записи кэша, чьи filter могли бы относиться к этой вакансии
+	// либо до, либо после правки (значение могло и перестать, и начать подходить).
+	invalidateVacanciesCache(oldRow)
+	invalidateVacanciesCache(vacancyFilterRow(updated))
+	eventsHub.Publish("vacancies", "vacancy.updated", updated)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(updated)
 }
@@ -235,34 +347,130 @@ func updateVacancyHandler(w http.ResponseWriter, r *http.Request) {
 // deleteVacancyHandler удаляет вакансию по ID.
 func deleteVacancyHandler(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
+	var v Vacancy
+	if err := db.First(&v, id).Error; err != nil {
+		http.Error(w, "Вакансия не найдена", http.StatusNotFound)
+		return
+	}
 	if err := db.Delete(&Vacancy{}, id).Error; err != nil {
 		http.Error(w, "Ошибка удаления вакансии", http.StatusInternalServerError)
 		return
 	}
-	// Инвалидируем кэш
-	invalidateVacanciesCache()
+	// Инвалидируем записи кэша, чьи filter могли бы относиться к удалённой строке.
+	invalidateVacanciesCache(vacancyFilterRow(v))
+	eventsHub.Publish("vacancies", "vacancy.deleted", map[string]string{"id": id})
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// getAdminVacanciesHandler возвращает все вакансии (админ-версия).
+// scopeByAccess ограничивает dbq строками resource, которые user разрешено
+// читать/писать (op) согласно точечным ACL-грантам поверх решения для всего
+// типа ресурса: при глобальном allow исключает явно запрещённые ID, при
+// глобальном deny — допускает только явно разрешённые (или не возвращает
+// ничего, если таких нет). Так per-ID гранты реально сужают SQL-выборку в
+// списковых хендлерах, а не только проверяются в protect() для одиночных.
+func scopeByAccess(dbq *gorm.DB, user *auth.User, resource auth.Resource, op auth.Op) (*gorm.DB, error) {
+	allowAll, allow, deny, err := authMgr.AccessibleIDs(user, resource, op)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !allowAll && len(allow) == 0:
+		return dbq.Where("1 = 0"), nil
+	case !allowAll:
+		return dbq.Where("id IN ?", allow), nil
+	case len(deny) > 0:
+		return dbq.Where("id NOT IN ?", deny), nil
+	default:
+		return dbq, nil
+	}
+}
+
+// getAdminVacanciesHandler возвращает вакансии, видимые текущему пользователю:
+// поверх фильтрации/сортировки/пагинации накладывается scopeByAccess. В
+// отличие от публичного листинга результат не кэшируется — vacanciesListCache
+// общий на все запросы, а здесь выборка зависит от грантов пользователя.
 func getAdminVacanciesHandler(w http.ResponseWriter, r *http.Request) {
-	getVacanciesHandler(w, r)
+	q, err := vacancyColumns.Parse(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	user, ok := auth.UserFromContext(r)
+	if !ok {
+		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		return
+	}
+
+	countScope, err := scopeByAccess(db.Model(&Vacancy{}), user, auth.ResourceVacancy, auth.OpRead)
+	if err != nil {
+		http.Error(w, "Ошибка проверки доступа", http.StatusInternalServerError)
+		return
+	}
+	var total int64
+	if err := q.Filtered(countScope, vacancyColumns.Searchable).Count(&total).Error; err != nil {
+		http.Error(w, "Ошибка запроса вакансий", http.StatusInternalServerError)
+		return
+	}
+
+	findScope, err := scopeByAccess(db.Model(&Vacancy{}), user, auth.ResourceVacancy, auth.OpRead)
+	if err != nil {
+		http.Error(w, "Ошибка проверки доступа", http.StatusInternalServerError)
+		return
+	}
+	var vacancies []Vacancy
+	if err := q.Apply(findScope, vacancyColumns.Searchable).Find(&vacancies).Error; err != nil {
+		http.Error(w, "Ошибка запроса вакансий", http.StatusInternalServerError)
+		return
+	}
+
+	writeEnvelope(w, r, q, listing.NewEnvelope(vacancies, q, total))
 }
 
 // ----------------------
 // ADMIN API - ЗАЯВКИ
 // ----------------------
 
-// getApplicationsHandler возвращает все заявки.
+// getApplicationsHandler возвращает заявки, видимые текущему пользователю:
+// поверх фильтрации/сортировки/пагинации накладывается scopeByAccess, так
+// что, например, гранты {application, *, deny}, {application, 4, read-only}
+// и {application, 7, read-only} сузят список именно до заявок 4 и 7, а не
+// просто пройдут/провалят проверку целиком.
 func getApplicationsHandler(w http.ResponseWriter, r *http.Request) {
+	q, err := applicationColumns.Parse(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	user, ok := auth.UserFromContext(r)
+	if !ok {
+		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		return
+	}
+
+	countScope, err := scopeByAccess(db.Model(&Application{}), user, auth.ResourceApplication, auth.OpRead)
+	if err != nil {
+		http.Error(w, "Ошибка проверки доступа", http.StatusInternalServerError)
+		return
+	}
+	var total int64
+	if err := q.Filtered(countScope, applicationColumns.Searchable).Count(&total).Error; err != nil {
+		http.Error(w, "Ошибка запроса заявок", http.StatusInternalServerError)
+		return
+	}
+
+	findScope, err := scopeByAccess(db.Model(&Application{}), user, auth.ResourceApplication, auth.OpRead)
+	if err != nil {
+		http.Error(w, "Ошибка проверки доступа", http.StatusInternalServerError)
+		return
+	}
 	var apps []Application
-	if err := db.Find(&apps).Error; err != nil {
+	if err := q.Apply(findScope, applicationColumns.Searchable).Find(&apps).Error; err != nil {
 		http.Error(w, "Ошибка запроса заявок", http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{"applications": apps})
+
+	writeEnvelope(w, r, q, listing.NewEnvelope(apps, q, total))
 }
 
 // getApplicationHandler возвращает заявку по ID.
@@ -293,6 +501,7 @@ func updateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Ошибка обновления заявки", http.StatusInternalServerError)
 		return
 	}
+	eventsHub.Publish("applications", "application.status_changed", app)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(app)
 }
@@ -334,10 +543,247 @@ func sendEmailHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "email sent"})
 }
 
+// sendTemplateHandler рендерит именованный шаблон письма (с подстановкой
+// заявки, связанной вакансии и переданных vars) и отправляет его заявителю,
+// записывая результат в журнал email.Log.
+func sendTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	var app Application
+	if err := db.First(&app, id).Error; err != nil {
+		http.Error(w, "Заявка не найдена", http.StatusNotFound)
+		return
+	}
+	var vacancy Vacancy
+	if err := db.First(&vacancy, app.VacancyID).Error; err != nil {
+		http.Error(w, "Вакансия заявки не найдена", http.StatusNotFound)
+		return
+	}
+
+	var payload struct {
+		Template string            `json:"template"`
+		Vars     map[string]string `json:"vars"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := email.Context{
+		Application: email.ApplicationView{
+			ID:                app.ID,
+			Name:              app.Name,
+			PrimaryContact:    app.PrimaryContact,
+			About:             app.About,
+			Status:            app.Status,
+			SalaryExpectation: app.SalaryExpectation,
+			AvailableFrom:     app.AvailableFrom,
+			CreatedAt:         app.CreatedAt,
+		},
+		Vacancy: email.VacancyView{ID: vacancy.ID, Title: vacancy.Title, Subtitle: vacancy.Subtitle},
+		Now:     time.Now(),
+		Lang:    cfg.EmailLocale,
+		Vars:    payload.Vars,
+	}
+
+	subject, html, text, err := emailTemplates.Render(payload.Template, ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sendErr := emailSender.Send(app.PrimaryContact, email.Message{Subject: subject, Text: text, HTML: html}, ctx.Now)
+	if logErr := emailLogs.Record(app.ID, payload.Template, app.PrimaryContact, subject, sendErr); logErr != nil {
+		log.Printf("Ошибка записи журнала писем: %v\n", logErr)
+	}
+	if sendErr != nil {
+		http.Error(w, "Ошибка отправки письма: "+sendErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	eventsHub.Publish("applications", "email.sent", map[string]interface{}{"applicationId": app.ID, "template": payload.Template})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "email sent"})
+}
+
+// listApplicationEmailsHandler возвращает журнал писем, отправленных по заявке.
+func listApplicationEmailsHandler(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Неверный формат ID", http.StatusBadRequest)
+		return
+	}
+	logs, err := emailLogs.ForApplication(uint(id))
+	if err != nil {
+		http.Error(w, "Ошибка запроса журнала писем", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"emails": logs})
+}
+
+// eventResourceInfo сообщает, к какому ресурсу относится событие шины и в
+// каком поле его Data лежит ID этого ресурса — используется eventAllowed,
+// чтобы применить CheckAccess перед отправкой события подписчику SSE.
+var eventResourceInfo = map[string]struct {
+	Resource auth.Resource
+	IDField  string
+}{
+	"application.created":        {auth.ResourceApplication, "id"},
+	"application.status_changed": {auth.ResourceApplication, "id"},
+	"email.sent":                 {auth.ResourceApplication, "applicationId"},
+	"vacancy.created":            {auth.ResourceVacancy, "id"},
+	"vacancy.updated":            {auth.ResourceVacancy, "id"},
+	"vacancy.deleted":            {auth.ResourceVacancy, "id"},
+}
+
+// eventAllowed решает, может ли user увидеть событие ev в SSE-потоке, сверяя
+// ACL-гранты через CheckAccess — так же, как getApplicationsHandler и
+// getAdminVacanciesHandler сужают свою выборку через scopeByAccess. Событие
+// неизвестного типа или без распознанного ID отклоняется (fail-closed).
+func eventAllowed(user *auth.User, ev events.Event) bool {
+	info, ok := eventResourceInfo[ev.Name]
+	if !ok {
+		return false
+	}
+
+	raw, err := json.Marshal(ev.Data)
+	if err != nil {
+		return false
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false
+	}
+
+	var id string
+	switch v := fields[info.IDField].(type) {
+	case string:
+		id = v
+	case float64:
+		id = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return false
+	}
+
+	return authMgr.CheckAccess(user, info.Resource, id, auth.OpRead)
+}
+
+// eventsHandler отдаёт поток Server-Sent Events по топикам из ?topics=a,b,c
+// (applications, vacancies), которые публикуют applyHandler и хендлеры
+// вакансий/заявок при каждом изменении. Каждое событие сверяется с ACL
+// текущего пользователя через eventAllowed, прежде чем попасть в поток.
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	topics := events.ParseTopics(r.URL.Query().Get("topics"))
+	if len(topics) == 0 {
+		http.Error(w, "Нужно указать ?topics=applications,vacancies", http.StatusBadRequest)
+		return
+	}
+	user, ok := auth.UserFromContext(r)
+	if !ok {
+		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		return
+	}
+	eventsHub.ServeSSE(w, r, topics, func(ev events.Event) bool {
+		return eventAllowed(user, ev)
+	})
+}
+
+// protect оборачивает хендлер сессионной проверкой роли (RequireAuth) и
+// проверкой точечного доступа к ресурсу (RequireAccess): сначала нужна
+// минимальная роль minRole, затем CheckAccess должен разрешить op над
+// resource/{idParam}. idParam == "" означает операцию над всей коллекцией
+// ("*"), а не конкретным ID.
+func protect(minRole auth.Role, resource auth.Resource, op auth.Op, idParam string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authMgr.RequireAuth(minRole)(authMgr.RequireAccess(resource, op, idParam)(next))
+	}
+}
+
 // ----------------------
 // MAIN
 // ----------------------
+// adduserCommand — CLI-подкоманда "adduser -email ... -password ... -role ...",
+// позволяющая завести пользователя admin-панели без HTTP-запроса.
+func adduserCommand(args []string) {
+	fs := flag.NewFlagSet("adduser", flag.ExitOnError)
+	email := fs.String("email", "", "email нового пользователя")
+	password := fs.String("password", "", "пароль нового пользователя")
+	role := fs.String("role", string(auth.RoleViewer), "роль: admin, editor или viewer")
+	fs.Parse(args)
+
+	if *email == "" || *password == "" {
+		log.Fatal("Нужно указать -email и -password")
+	}
+
+	var err error
+	cfg, err = loadConfig("config.json")
+	if err != nil {
+		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	db, err = gorm.Open(sqlite.Open(cfg.DBSource), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Ошибка подключения к БД: %v", err)
+	}
+	authMgr = auth.NewManager(db, []byte(cfg.SessionKey), cfg.SessionSecureCookies)
+	if err := authMgr.Migrate(); err != nil {
+		log.Fatalf("Ошибка миграции: %v", err)
+	}
+	if _, err := authMgr.CreateUser(*email, *password, auth.Role(*role)); err != nil {
+		log.Fatalf("Ошибка создания пользователя: %v", err)
+	}
+	log.Printf("Пользователь %s создан с ролью %s\n", *email, *role)
+}
+
+// accessCommand — CLI-подкоманда "access <email> <resource>[/<id>] <perm>",
+// выдающая пользователю точечный грант без обращения к HTTP API.
+func accessCommand(args []string) {
+	if len(args) != 3 {
+		log.Fatal("Использование: access <email> <resource>[/<id>] <permission>")
+	}
+	userEmail, resourceSpec, perm := args[0], args[1], auth.Permission(args[2])
+
+	resource, resourceID, err := auth.ParseResourceSpec(resourceSpec)
+	if err != nil {
+		log.Fatalf("Ошибка разбора ресурса: %v", err)
+	}
+
+	cfg, err = loadConfig("config.json")
+	if err != nil {
+		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	db, err = gorm.Open(sqlite.Open(cfg.DBSource), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Ошибка подключения к БД: %v", err)
+	}
+	authMgr = auth.NewManager(db, []byte(cfg.SessionKey), cfg.SessionSecureCookies)
+	if err := authMgr.Migrate(); err != nil {
+		log.Fatalf("Ошибка миграции: %v", err)
+	}
+	if err := authMgr.MigrateACL(); err != nil {
+		log.Fatalf("Ошибка миграции доступа: %v", err)
+	}
+
+	var user auth.User
+	if err := db.Where("email = ?", userEmail).First(&user).Error; err != nil {
+		log.Fatalf("Пользователь %s не найден: %v", userEmail, err)
+	}
+	if _, err := authMgr.UpsertGrant(user.ID, resource, resourceID, perm); err != nil {
+		log.Fatalf("Ошибка выдачи доступа: %v", err)
+	}
+	log.Printf("%s получил %s на %s/%s\n", userEmail, perm, resource, resourceID)
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "adduser" {
+		adduserCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "access" {
+		accessCommand(os.Args[2:])
+		return
+	}
+
 	// Загрузка конфигурации
 	var err error
 	cfg, err = loadConfig("config.json")
@@ -356,6 +802,36 @@ func main() {
 		log.Fatalf("Ошибка миграции: %v", err)
 	}
 
+	// Инициализация подсистемы аутентификации и первого администратора.
+	authMgr = auth.NewManager(db, []byte(cfg.SessionKey), cfg.SessionSecureCookies)
+	if err := authMgr.Migrate(); err != nil {
+		log.Fatalf("Ошибка миграции пользователей: %v", err)
+	}
+	if err := authMgr.EnsureBootstrapAdmin(cfg.BootstrapAdminEmail, cfg.BootstrapAdminPassword); err != nil {
+		log.Fatalf("Ошибка создания администратора по умолчанию: %v", err)
+	}
+	if err := authMgr.MigrateACL(); err != nil {
+		log.Fatalf("Ошибка миграции доступа: %v", err)
+	}
+
+	// Инициализация подсистемы писем: шаблоны, отправитель, журнал отправок.
+	emailTemplates, err = email.LoadTemplates(cfg.TemplatesDir)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки шаблонов писем: %v", err)
+	}
+	emailSender = email.NewSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword)
+	emailLogs, err = email.NewLogStore(db)
+	if err != nil {
+		log.Fatalf("Ошибка миграции журнала писем: %v", err)
+	}
+
+	// Инициализация подсистемы вебхуков: те же события, что видит SSE-хаб.
+	webhookDispatcher, err = webhook.NewDispatcher(db)
+	if err != nil {
+		log.Fatalf("Ошибка миграции вебхуков: %v", err)
+	}
+	webhookDispatcher.Start(context.Background(), eventsHub)
+
 	// Настройка роутера
 	r := mux.NewRouter()
 
@@ -371,17 +847,39 @@ func main() {
 	})
 	r.PathPrefix("/api").Handler(publicCors.Handler(public))
 
-	// ADMIN маршруты (под префиксом /admin) – только с доменов, указанных в конфигурации.
+	// ADMIN маршруты (под префиксом /admin) – защищены сессией и CORS-доменами из конфигурации.
 	admin := mux.NewRouter()
-	admin.HandleFunc("/vacancy", addVacancyHandler).Methods("POST")
-	admin.HandleFunc("/vacancy/{id}", updateVacancyHandler).Methods("PUT")
-	admin.HandleFunc("/vacancy/{id}", deleteVacancyHandler).Methods("DELETE")
-	admin.HandleFunc("/vacancies", getAdminVacanciesHandler).Methods("GET")
-	admin.HandleFunc("/applications", getApplicationsHandler).Methods("GET")
-	admin.HandleFunc("/application/{id}", getApplicationHandler).Methods("GET")
-	admin.HandleFunc("/application/{id}", updateApplicationHandler).Methods("PUT")
-	admin.HandleFunc("/application/{id}", deleteApplicationHandler).Methods("DELETE")
-	admin.HandleFunc("/application/{id}/send-email", sendEmailHandler).Methods("POST")
+
+	// Аутентификация: логин/логаут доступны без сессии, регистрация — только администратору.
+	admin.HandleFunc("/auth/login", authMgr.LoginHandler).Methods("POST")
+	admin.Handle("/auth/logout", authMgr.RequireAuth(auth.RoleViewer)(http.HandlerFunc(authMgr.LogoutHandler))).Methods("POST")
+	admin.Handle("/auth/register", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(authMgr.RegisterHandler))).Methods("POST")
+
+	admin.Handle("/vacancy", protect(auth.RoleEditor, auth.ResourceVacancy, auth.OpWrite, "")(http.HandlerFunc(addVacancyHandler))).Methods("POST")
+	admin.Handle("/vacancy/{id}", protect(auth.RoleEditor, auth.ResourceVacancy, auth.OpWrite, "id")(http.HandlerFunc(updateVacancyHandler))).Methods("PUT")
+	admin.Handle("/vacancy/{id}", protect(auth.RoleEditor, auth.ResourceVacancy, auth.OpWrite, "id")(http.HandlerFunc(deleteVacancyHandler))).Methods("DELETE")
+	// Списки проверяют доступ сами через scopeByAccess: RequireAccess на "*"
+	// отклонил бы пользователя с {resource, *, deny} ещё до сужения по ID.
+	admin.Handle("/vacancies", authMgr.RequireAuth(auth.RoleViewer)(http.HandlerFunc(getAdminVacanciesHandler))).Methods("GET")
+	admin.Handle("/applications", authMgr.RequireAuth(auth.RoleViewer)(http.HandlerFunc(getApplicationsHandler))).Methods("GET")
+	admin.Handle("/application/{id}", protect(auth.RoleViewer, auth.ResourceApplication, auth.OpRead, "id")(http.HandlerFunc(getApplicationHandler))).Methods("GET")
+	admin.Handle("/application/{id}", protect(auth.RoleEditor, auth.ResourceApplication, auth.OpWrite, "id")(http.HandlerFunc(updateApplicationHandler))).Methods("PUT")
+	admin.Handle("/application/{id}", protect(auth.RoleEditor, auth.ResourceApplication, auth.OpWrite, "id")(http.HandlerFunc(deleteApplicationHandler))).Methods("DELETE")
+	admin.Handle("/application/{id}/send-email", protect(auth.RoleEditor, auth.ResourceApplication, auth.OpWrite, "id")(http.HandlerFunc(sendEmailHandler))).Methods("POST")
+	admin.Handle("/application/{id}/send-template", protect(auth.RoleEditor, auth.ResourceApplication, auth.OpWrite, "id")(http.HandlerFunc(sendTemplateHandler))).Methods("POST")
+	admin.Handle("/application/{id}/emails", protect(auth.RoleViewer, auth.ResourceApplication, auth.OpRead, "id")(http.HandlerFunc(listApplicationEmailsHandler))).Methods("GET")
+	admin.Handle("/events", authMgr.RequireAuth(auth.RoleViewer)(http.HandlerFunc(eventsHandler))).Methods("GET")
+
+	admin.Handle("/users/{id}/access", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(authMgr.ListAccessHandler))).Methods("GET")
+	admin.Handle("/users/{id}/access", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(authMgr.CreateAccessHandler))).Methods("POST")
+	admin.Handle("/users/{id}/access/{grantId}", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(authMgr.DeleteAccessHandler))).Methods("DELETE")
+
+	admin.Handle("/webhooks", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(webhookDispatcher.ListHandler))).Methods("GET")
+	admin.Handle("/webhooks", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(webhookDispatcher.CreateHandler))).Methods("POST")
+	admin.Handle("/webhooks/{id}", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(webhookDispatcher.UpdateHandler))).Methods("PUT")
+	admin.Handle("/webhooks/{id}", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(webhookDispatcher.DeleteHandler))).Methods("DELETE")
+	admin.Handle("/webhooks/{id}/deliveries", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(webhookDispatcher.ListDeliveriesHandler))).Methods("GET")
+	admin.Handle("/webhooks/{id}/deliveries/{deliveryId}/redeliver", authMgr.RequireAuth(auth.RoleAdmin)(http.HandlerFunc(webhookDispatcher.RedeliverHandler))).Methods("POST")
 
 	adminCors := cors.New(cors.Options{
 		AllowedOrigins:   cfg.AdminAllowedOrigins,
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/datatypes"
+)
+
+// webhookPayload — тело запроса создания/обновления вебхука.
+type webhookPayload struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+}
+
+// ListHandler отдаёт все зарегистрированные вебхуки.
+func (d *Dispatcher) ListHandler(w http.ResponseWriter, r *http.Request) {
+	var hooks []Webhook
+	if err := d.db.Order("created_at desc").Find(&hooks).Error; err != nil {
+		http.Error(w, "Ошибка выборки вебхуков", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hooks)
+}
+
+// CreateHandler регистрирует новый вебхук.
+func (d *Dispatcher) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	var payload webhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	events, err := json.Marshal(payload.Events)
+	if err != nil {
+		http.Error(w, "Неверный список событий", http.StatusBadRequest)
+		return
+	}
+
+	hook := Webhook{
+		URL:    payload.URL,
+		Secret: payload.Secret,
+		Events: datatypes.JSON(events),
+		Active: payload.Active,
+	}
+	if err := d.db.Create(&hook).Error; err != nil {
+		http.Error(w, "Ошибка создания вебхука: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+// UpdateHandler обновляет URL/секрет/список событий/активность вебхука.
+func (d *Dispatcher) UpdateHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	var hook Webhook
+	if err := d.db.First(&hook, id).Error; err != nil {
+		http.Error(w, "Вебхук не найден", http.StatusNotFound)
+		return
+	}
+
+	var payload webhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	events, err := json.Marshal(payload.Events)
+	if err != nil {
+		http.Error(w, "Неверный список событий", http.StatusBadRequest)
+		return
+	}
+
+	hook.URL = payload.URL
+	hook.Secret = payload.Secret
+	hook.Events = datatypes.JSON(events)
+	hook.Active = payload.Active
+	if err := d.db.Save(&hook).Error; err != nil {
+		http.Error(w, "Ошибка обновления вебхука: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hook)
+}
+
+// DeleteHandler удаляет вебхук. История его доставок сохраняется.
+func (d *Dispatcher) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := d.db.Delete(&Webhook{}, id).Error; err != nil {
+		http.Error(w, "Ошибка удаления вебхука", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveriesHandler отдаёт доставки вебхука, от новых к старым: по строке
+// на доставку с результатом её последней попытки.
+func (d *Dispatcher) ListDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID := mux.Vars(r)["id"]
+	var deliveries []WebhookDelivery
+	if err := d.db.Where("webhook_id = ?", webhookID).Order("created_at desc").Find(&deliveries).Error; err != nil {
+		http.Error(w, "Ошибка выборки доставок", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// RedeliverHandler ставит в очередь повторную отправку уже существующей доставки.
+func (d *Dispatcher) RedeliverHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Неверный формат ID вебхука", http.StatusBadRequest)
+		return
+	}
+	deliveryID, err := strconv.ParseUint(mux.Vars(r)["deliveryId"], 10, 64)
+	if err != nil {
+		http.Error(w, "Неверный формат ID доставки", http.StatusBadRequest)
+		return
+	}
+
+	if err := d.Redeliver(uint(webhookID), uint(deliveryID)); err != nil {
+		http.Error(w, "Доставка не найдена", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
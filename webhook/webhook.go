@@ -0,0 +1,304 @@
+// Package webhook рассылает события шины events.Hub во внешние системы
+// (ATS, Slack-мосты, аналитику) по HTTP. Dispatcher подписывается на те же
+// топики, что и SSE (см. events.Hub), поэтому оба транспорта видят
+// идентичный поток событий.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"vacancies_service/events"
+)
+
+// Webhook — подписка внешней системы на события приложения.
+type Webhook struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	URL       string         `json:"url"`
+	Secret    string         `json:"secret"`
+	Events    datatypes.JSON `json:"events"` // JSON-массив имён событий, например ["application.created"]
+	Active    bool           `json:"active"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// eventNames разбирает Events в срез строк.
+func (w Webhook) eventNames() []string {
+	var names []string
+	json.Unmarshal(w.Events, &names)
+	return names
+}
+
+// matches сообщает, подписан ли webhook на событие name.
+func (w Webhook) matches(name string) bool {
+	if !w.Active {
+		return false
+	}
+	for _, n := range w.eventNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// статусы WebhookDelivery.
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// maxAttempts — предельное число попыток доставки.
+const maxAttempts = 6
+
+// backoff — задержки перед повторными попытками: backoff[n-1] — пауза после
+// неудачной попытки n (попытки считаются с 1).
+var backoff = []time.Duration{
+	time.Second,
+	5 * time.Second,
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+}
+
+// WebhookDelivery фиксирует доставку одного события на конкретный webhook.
+// Каждая попытка перезаписывает Attempt/Status/ResponseCode/Error/NextAttempt
+// в той же строке: хранится только результат последней попытки, история
+// отдельных попыток не сохраняется.
+type WebhookDelivery struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	WebhookID    uint      `gorm:"index" json:"webhookId"`
+	DeliveryUUID string    `gorm:"index" json:"deliveryUuid"`
+	Event        string    `json:"event"`
+	Payload      string    `json:"payload"`
+	Attempt      int       `json:"attempt"`
+	Status       string    `json:"status"` // pending, success, failed
+	ResponseCode int       `json:"responseCode,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	NextAttempt  time.Time `json:"nextAttempt,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// topics — топики events.Hub, на которые реагируют вебхуки.
+var topics = []string{"vacancies", "applications"}
+
+// workerCount — число горутин, обрабатывающих очередь доставки.
+const workerCount = 4
+
+// queueSize — ёмкость канала задач; при переполнении публикация события не блокируется.
+const queueSize = 256
+
+// Dispatcher слушает events.Hub и рассылает совпадающие события на
+// зарегистрированные вебхуки силами пула воркеров, с повторными попытками.
+type Dispatcher struct {
+	db     *gorm.DB
+	client *http.Client
+	queue  chan deliveryJob
+}
+
+type deliveryJob struct {
+	delivery WebhookDelivery
+	webhook  Webhook
+}
+
+// NewDispatcher создаёт Dispatcher и мигрирует таблицы Webhook/WebhookDelivery.
+func NewDispatcher(db *gorm.DB) (*Dispatcher, error) {
+	if err := db.AutoMigrate(&Webhook{}, &WebhookDelivery{}); err != nil {
+		return nil, err
+	}
+	return &Dispatcher{
+		db:     db,
+		client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan deliveryJob, queueSize),
+	}, nil
+}
+
+// Start запускает пул воркеров и подписывается на топики hub, рассылая
+// каждое подходящее событие на все активные вебхуки, подписанные на его имя.
+// Останавливается при отмене ctx.
+func (d *Dispatcher) Start(ctx context.Context, hub *events.Hub) {
+	for i := 0; i < workerCount; i++ {
+		go d.worker(ctx)
+	}
+
+	for _, topic := range topics {
+		ch, cancel := hub.Subscribe(topic)
+		go func(ch <-chan events.Event, cancel func()) {
+			defer cancel()
+			for {
+				select {
+				case ev, ok := <-ch:
+					if !ok {
+						return
+					}
+					d.dispatch(ev)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch, cancel)
+	}
+}
+
+// dispatch ставит в очередь доставку события ev на каждый подходящий вебхук.
+func (d *Dispatcher) dispatch(ev events.Event) {
+	var hooks []Webhook
+	if err := d.db.Where("active = ?", true).Find(&hooks).Error; err != nil {
+		log.Printf("webhook: ошибка выборки подписок: %v", err)
+		return
+	}
+
+	payload, err := json.Marshal(ev.Data)
+	if err != nil {
+		log.Printf("webhook: ошибка сериализации события %s: %v", ev.Name, err)
+		return
+	}
+
+	for _, hook := range hooks {
+		if !hook.matches(ev.Name) {
+			continue
+		}
+		delivery := WebhookDelivery{
+			WebhookID:    hook.ID,
+			DeliveryUUID: uuid.NewString(),
+			Event:        ev.Name,
+			Payload:      string(payload),
+			Status:       StatusPending,
+		}
+		if err := d.db.Create(&delivery).Error; err != nil {
+			log.Printf("webhook: ошибка сохранения доставки: %v", err)
+			continue
+		}
+		d.enqueue(deliveryJob{delivery: delivery, webhook: hook})
+	}
+}
+
+// enqueue кладёт задачу в очередь, не блокируя вызывающего при переполнении —
+// задача всё равно останется в БД со статусом pending и будет переотправлена вручную.
+func (d *Dispatcher) enqueue(job deliveryJob) {
+	select {
+	case d.queue <- job:
+	default:
+		log.Printf("webhook: очередь доставки переполнена, доставка %s отложена", job.delivery.DeliveryUUID)
+	}
+}
+
+func (d *Dispatcher) worker(ctx context.Context) {
+	for {
+		select {
+		case job := <-d.queue:
+			d.attempt(job)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// attempt выполняет одну попытку доставки и либо завершает её успехом, либо
+// планирует следующую попытку с экспоненциальной задержкой, либо помечает
+// доставку окончательно неудавшейся по исчерпании maxAttempts.
+func (d *Dispatcher) attempt(job deliveryJob) {
+	delivery, hook := job.delivery, job.webhook
+
+	delivery.Attempt++
+	code, sendErr := d.send(hook, delivery)
+
+	delivery.ResponseCode = code
+	if sendErr == nil && code >= 200 && code < 300 {
+		delivery.Status = StatusSuccess
+		delivery.Error = ""
+	} else {
+		if sendErr != nil {
+			delivery.Error = sendErr.Error()
+		} else {
+			delivery.Error = http.StatusText(code)
+		}
+		if delivery.Attempt >= maxAttempts {
+			delivery.Status = StatusFailed
+		} else {
+			delivery.Status = StatusPending
+			delay := backoff[len(backoff)-1]
+			if delivery.Attempt-1 < len(backoff) {
+				delay = backoff[delivery.Attempt-1]
+			}
+			delivery.NextAttempt = time.Now().Add(delay)
+		}
+	}
+
+	if err := d.db.Save(&delivery).Error; err != nil {
+		log.Printf("webhook: ошибка обновления доставки %s: %v", delivery.DeliveryUUID, err)
+	}
+
+	if delivery.Status == StatusPending && delivery.Attempt < maxAttempts {
+		delay := time.Until(delivery.NextAttempt)
+		time.AfterFunc(delay, func() {
+			d.enqueue(deliveryJob{delivery: delivery, webhook: hook})
+		})
+	}
+}
+
+// send отправляет один HTTP-запрос доставки и возвращает код ответа.
+func (d *Dispatcher) send(hook Webhook, delivery WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event", delivery.Event)
+	req.Header.Set("X-Delivery", delivery.DeliveryUUID)
+	req.Header.Set("X-Signature", "sha256="+sign(hook.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// sign возвращает hex-представление HMAC-SHA256 тела запроса с секретом вебхука.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Redeliver ставит в очередь повторную отправку уже существующей доставки
+// (используется POST /admin/webhooks/{id}/deliveries/{deliveryId}/redeliver).
+func (d *Dispatcher) Redeliver(webhookID, deliveryID uint) error {
+	var hook Webhook
+	if err := d.db.First(&hook, webhookID).Error; err != nil {
+		return err
+	}
+	var delivery WebhookDelivery
+	if err := d.db.Where("id = ? AND webhook_id = ?", deliveryID, webhookID).First(&delivery).Error; err != nil {
+		return err
+	}
+
+	delivery.Status = StatusPending
+	delivery.Error = ""
+	delivery.ResponseCode = 0
+	delivery.Attempt = 0 // ручной redeliver даёт полный бюджет попыток заново
+	if err := d.db.Save(&delivery).Error; err != nil {
+		return err
+	}
+	d.enqueue(deliveryJob{delivery: delivery, webhook: hook})
+	return nil
+}